@@ -28,9 +28,7 @@ package main
 
 import (
     "bufio"
-    "bytes"
     "errors"
-    "io"
     "flag"
     "fmt"
     "log"
@@ -38,23 +36,85 @@ import (
     "os/exec"
     "sort"
     "strings"
+    "syscall"
+    "time"
 
     ui "github.com/gizak/termui/v3"
     "github.com/gizak/termui/v3/widgets"
+
+    prompt "github.com/hwpplayers/chaifi/internal/tui"
 )
 
 type ColorScheme int
 
+// SortKey picks which Network field the list is currently ordered by;
+// cycled with the "s" key.
+type SortKey int
+
+// Auth identifies the key management scheme a network uses, driving
+// both config generation (genNetworkEntry) and which TUI prompt is
+// shown when adding the network.
+type Auth int
+
+const (
+    AuthOpen Auth = iota
+    AuthWEP
+    AuthWPAPersonal
+    AuthWPAEnterprise
+    AuthWPA3SAE
+    AuthOWE
+)
+
+func (a Auth) String() string {
+    switch a {
+    case AuthWEP:
+        return "WEP"
+    case AuthWPAPersonal:
+        return "WPA"
+    case AuthWPAEnterprise:
+        return "EAP"
+    case AuthWPA3SAE:
+        return "WPA3"
+    case AuthOWE:
+        return "OWE"
+    default:
+        return ""
+    }
+}
+
+// needsPassphrase reports whether adding this network should prompt for
+// a PSK/passphrase before it can be written to wpa_supplicant.conf.
+func (a Auth) needsPassphrase() bool {
+    switch a {
+    case AuthWPAPersonal, AuthWPA3SAE, AuthWEP:
+        return true
+    default:
+        return false
+    }
+}
+
 type Network struct {
     ssid, psk string
-    security bool
+    authType Auth
+    // 802.1X (WPA-Enterprise) credentials; eapMethod defaults to "PEAP"
+    // in genNetworkEntry when empty
+    eapMethod, identity, eapPassword, caCert, phase2 string
+    // populated by Scanner backends that expose them; zero value means
+    // "unknown" rather than "zero signal"
+    rssi, channel, frequency int
+    cipher string
+    lastSeen time.Time
 }
 
 type Tui struct {
+    header *widgets.Paragraph
     list *widgets.List
     help *widgets.Paragraph
-    password *widgets.Paragraph
+    passwordPrompt *prompt.Prompt
+    identityPrompt *prompt.Prompt
+    certPrompt *prompt.Prompt
     listWidth int
+    sortKey SortKey
 }
 
 const (
@@ -64,6 +124,112 @@ const (
     chaifiMarker = "# CHAIFI: DO NOT EDIT BELOW THIS LINE"
 )
 
+const (
+    sortSSID SortKey = iota
+    sortRSSI
+    sortChannel
+    sortLastSeen
+)
+
+func (k SortKey) String() string {
+    switch k {
+    case sortRSSI:
+        return "RSSI"
+    case sortChannel:
+        return "Channel"
+    case sortLastSeen:
+        return "Last seen"
+    default:
+        return "SSID"
+    }
+}
+
+// nextSortKey returns the SortKey that follows k in the cycle used by "s".
+func nextSortKey(k SortKey) SortKey {
+    switch k {
+    case sortSSID:
+        return sortRSSI
+    case sortRSSI:
+        return sortChannel
+    case sortChannel:
+        return sortLastSeen
+    default:
+        return sortSSID
+    }
+}
+
+// sortNetworks orders networks in place by key: strongest signal first,
+// lowest channel first, most recently seen first, or SSID alphabetically.
+func sortNetworks(networks []Network, key SortKey) {
+    sort.Slice(networks, func(i, j int) bool {
+        switch key {
+        case sortRSSI:
+            return networks[i].rssi > networks[j].rssi
+        case sortChannel:
+            return networks[i].channel < networks[j].channel
+        case sortLastSeen:
+            return networks[i].lastSeen.After(networks[j].lastSeen)
+        default:
+            return networks[i].ssid < networks[j].ssid
+        }
+    })
+}
+
+// rssiColor maps a signal strength in dBm to the termui color tag used
+// to render it, mirroring the thresholds of common site-survey tools.
+func rssiColor(rssi int) string {
+    switch {
+    case rssi >= -60:
+        return "green"
+    case rssi >= -75:
+        return "yellow"
+    default:
+        return "red"
+    }
+}
+
+// identityPromptTitle labels the 802.1X username prompt with the step
+// <Tab> would switch to: a CA cert path while a password is selected,
+// a password while a cert path is.
+func identityPromptTitle(useCertAuth bool) string {
+    if useCertAuth {
+        return "[ Username (Tab: use password) ]"
+    }
+    return "[ Username (Tab: use cert path) ]"
+}
+
+// validatePSK enforces wpa_supplicant's two accepted PSK forms: an
+// 8-63 character ASCII passphrase, or a raw 64-character hex key.
+func validatePSK(psk string) error {
+    if len(psk) == 64 && isHexString(psk) {
+        return nil
+    }
+    if len(psk) >= 8 && len(psk) <= 63 && isASCIIString(psk) {
+        return nil
+    }
+    return errors.New("PSK must be 8-63 ASCII characters or exactly 64 hex characters")
+}
+
+func isASCIIString(s string) bool {
+    for i := 0; i < len(s); i++ {
+        if s[i] > 127 {
+            return false
+        }
+    }
+    return true
+}
+
+func isHexString(s string) bool {
+    for i := 0; i < len(s); i++ {
+        c := s[i]
+        isHexDigit := (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+        if !isHexDigit {
+            return false
+        }
+    }
+    return true
+}
+
 // simplified escape algorithm: escape backslash(\) and double quotes
 func escapeString(val string) string {
     escaped := strings.ReplaceAll(val, "\\", "\\\\")
@@ -98,14 +264,48 @@ func genNetworkEntry(network Network) string {
         result += fmt.Sprintf("    ssid=\"%s\"\n", escapeString(network.ssid))
     }
 
-    keyMgmt := "NONE"
-    if network.security {
-        keyMgmt = "WPA-PSK"
-    }
-    result += fmt.Sprintf("    key_mgmt=%s\n", keyMgmt)
-
-    if network.psk != "" {
-        result += fmt.Sprintf("    psk=\"%s\"\n", escapeString(network.psk))
+    switch network.authType {
+    case AuthWEP:
+        result += "    key_mgmt=NONE\n"
+        result += "    auth_alg=SHARED\n"
+        if network.psk != "" {
+            result += fmt.Sprintf("    wep_key0=\"%s\"\n", escapeString(network.psk))
+        }
+    case AuthWPAPersonal:
+        result += "    key_mgmt=WPA-PSK\n"
+        if network.psk != "" {
+            result += fmt.Sprintf("    psk=\"%s\"\n", escapeString(network.psk))
+        }
+    case AuthWPA3SAE:
+        result += "    key_mgmt=SAE\n"
+        result += "    ieee80211w=2\n"
+        if network.psk != "" {
+            result += fmt.Sprintf("    psk=\"%s\"\n", escapeString(network.psk))
+        }
+    case AuthWPAEnterprise:
+        eapMethod := network.eapMethod
+        if eapMethod == "" {
+            eapMethod = "PEAP"
+        }
+        result += "    key_mgmt=WPA-EAP\n"
+        result += fmt.Sprintf("    eap=%s\n", eapMethod)
+        if network.identity != "" {
+            result += fmt.Sprintf("    identity=\"%s\"\n", escapeString(network.identity))
+        }
+        if network.eapPassword != "" {
+            result += fmt.Sprintf("    password=\"%s\"\n", escapeString(network.eapPassword))
+        }
+        if network.caCert != "" {
+            result += fmt.Sprintf("    ca_cert=\"%s\"\n", escapeString(network.caCert))
+        }
+        if network.phase2 != "" {
+            result += fmt.Sprintf("    phase2=\"%s\"\n", escapeString(network.phase2))
+        }
+    case AuthOWE:
+        result += "    key_mgmt=OWE\n"
+        result += "    ieee80211w=2\n"
+    default:
+        result += "    key_mgmt=NONE\n"
     }
 
     result += "}\n"
@@ -163,8 +363,35 @@ func loadConfFile(path string) ([]Network, error) {
             network.ssid = value
         case "psk":
             network.psk = value
+        case "wep_key0":
+            network.psk = value
         case "key_mgmt":
-            network.security = value == "WPA-PSK"
+            switch value {
+            case "WPA-PSK":
+                network.authType = AuthWPAPersonal
+            case "SAE":
+                network.authType = AuthWPA3SAE
+            case "WPA-EAP":
+                network.authType = AuthWPAEnterprise
+            case "OWE":
+                network.authType = AuthOWE
+            default:
+                network.authType = AuthOpen
+            }
+        case "auth_alg":
+            if value == "SHARED" && network.authType == AuthOpen {
+                network.authType = AuthWEP
+            }
+        case "eap":
+            network.eapMethod = value
+        case "identity":
+            network.identity = value
+        case "password":
+            network.eapPassword = value
+        case "ca_cert":
+            network.caCert = value
+        case "phase2":
+            network.phase2 = value
         default:
             // TODO: raise error
         }
@@ -177,74 +404,21 @@ func loadConfFile(path string) ([]Network, error) {
     return result, nil
 }
 
-func listScan(iface string) []Network {
-    result := []Network{}
-    cmd := exec.Command("ifconfig", "-v", iface, "list", "scan")
-    cmdOutput := &bytes.Buffer{}
-    cmd.Stdout = cmdOutput
-    err := cmd.Run()
-    if err != nil {
-        return nil
-    }
-    output := string(cmdOutput.Bytes())
-    lines := strings.Split(output, "\n")
-    if len(lines) < 1 {
-        return result
-    }
-    header := lines[0]
-    lines = lines[1:]
-    ssidEnd := strings.Index(header, "BSSID") - 1
-    if ssidEnd < 0 {
-        return result
-    }
-
-    for _, line := range lines {
-        if len(line) < ssidEnd + 1 {
-            continue
-        }
-        ssid := line[:ssidEnd]
-        ssid = strings.Trim(ssid, " ")
-
-        // For now just skip networks with empty SSID
-        if ssid == "" {
-            continue
-        }
-
-        found := false
-        for _, n := range result {
-            if n.ssid == ssid {
-                found = true
-                break
-            }
-        }
-        if found {
-            continue
-        }
-
-        security := false
-        wpaPos := strings.Index(line, "WPA<") - 1
-        rsnPos := strings.Index(line, "RSN<") - 1
-        if wpaPos > 0 || rsnPos > 0 {
-            security = true
-        }
-        network := Network {ssid: ssid, security: security}
-        result = append(result, network)
-    }
-
-    sort.Slice(result, func(i, j int) bool {
-       return result[i].ssid < result[j].ssid
-    })
-
-    return result
-}
-
 // update wpa_supplicant.conf (if required) and return
 // true if new file was written, otherwise false
-func updateConfFile(path string, networks []Network) bool {
+func updateConfFile(path string, networks []Network, useLock bool) bool {
     file, err := os.OpenFile(path, os.O_RDWR, 0600)
     if err != nil {
         log.Fatal(err)
     }
+    defer file.Close()
+
+    if useLock {
+        if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX); err != nil {
+            log.Fatal(err)
+        }
+        defer syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+    }
 
     newContent := ""
     oldContent := ""
@@ -274,17 +448,53 @@ func updateConfFile(path string, networks []Network) bool {
         return false
     }
 
-    file.Seek(0, io.SeekStart)
-    file.Truncate(0)
-    file.WriteString (newContent)
-    file.Close()
+    backupPath := path + ".bak"
+    if err := os.WriteFile(backupPath, []byte(oldContent), 0600); err != nil {
+        log.Fatal(err)
+    }
+
+    tmpPath := path + ".tmp"
+    tmpFile, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+    if err != nil {
+        log.Fatal(err)
+    }
+    if _, err := tmpFile.WriteString(newContent); err != nil {
+        tmpFile.Close()
+        log.Fatal(err)
+    }
+    if err := tmpFile.Sync(); err != nil {
+        tmpFile.Close()
+        log.Fatal(err)
+    }
+    if err := tmpFile.Close(); err != nil {
+        log.Fatal(err)
+    }
+    if err := os.Rename(tmpPath, path); err != nil {
+        log.Fatal(err)
+    }
 
     return true
 }
 
+// formatRSSI renders a fixed-width, color-coded signal strength field,
+// or blank spaces when a backend didn't report one.
+func formatRSSI(rssi int) string {
+    width := 7
+    if rssi == 0 {
+        return strings.Repeat(" ", width)
+    }
+    text := fmt.Sprintf("%ddBm", rssi)
+    for len(text) < width {
+        text = text + " "
+    }
+    return fmt.Sprintf("[%s](fg:%s)", text, rssiColor(rssi))
+}
+
 func updateTui(tui *Tui, networks []Network, knownNetworks []Network) {
     result := []string{}
 
+    tui.header.Text = fmt.Sprintf("Sort: [%s](fg:green) (press [s](fg:green) to change)", tui.sortKey)
+
     for _, net := range networks {
         if net.ssid == "" {
             continue
@@ -300,20 +510,20 @@ func updateTui(tui *Tui, networks []Network, knownNetworks []Network) {
         if found {
             foundMark = '+'
         }
-        security := ""
-        if net.security {
-            security = "WPA"
+        security := net.authType.String()
+        if net.cipher != "" {
+            security = fmt.Sprintf("%s/%s", security, net.cipher)
         }
-        // substract prefix part and security suffix part
+        // substract prefix part, RSSI column, security suffix part,
         // scrollbar and borders
-        ssidW := tui.listWidth - 5 - 5 - 1 - 2
+        ssidW := tui.listWidth - 5 - 7 - 5 - 1 - 2
         spaces := ssidW - len(net.ssid)
         ssid := net.ssid
         for spaces > 0 {
             spaces -= 1
             ssid = ssid + " "
         }
-        row := fmt.Sprintf(" [%c] %s %s", foundMark, ssid, security)
+        row := fmt.Sprintf(" [%c] %s %s %s", foundMark, ssid, formatRSSI(net.rssi), security)
         result = append(result, row)
     }
     tui.list.Rows = result
@@ -346,16 +556,19 @@ func newTui(scheme ColorScheme) *Tui {
     tui.list.Title = "[ WiFi Networks ]"
     tui.list.WrapText = false
 
+    // header showing the current sort order
+    tui.header = widgets.NewParagraph()
+    tui.header.Border = false
+
     // status line with key help
     tui.help = widgets.NewParagraph()
-    tui.help.Text = "[a](fg:green) - add network, [x](fg:green) - delete network, [q](fg:green) - quit"
+    tui.help.Text = "[a](fg:green) - add network, [x](fg:green) - delete network, [s](fg:green) - sort, [q](fg:green) - quit"
     tui.help.Border = false
 
-    // password entry field
-    tui.password = widgets.NewParagraph()
-    tui.password.Title = "[ Password ]"
-    tui.password.Text = ""
-    tui.password.Border = true
+    // password, 802.1X identity and CA cert path entry fields
+    tui.passwordPrompt = prompt.New("[ Password ]", true)
+    tui.identityPrompt = prompt.New(identityPromptTitle(false), false)
+    tui.certPrompt = prompt.New("[ CA Cert Path ]", false)
 
     // set color style
     if scheme == darkScheme {
@@ -365,10 +578,13 @@ func newTui(scheme ColorScheme) *Tui {
         tui.list.SelectedRowStyle = ui.NewStyle(ui.ColorBlack, ColorLightWhite)
 
         tui.help.TextStyle = ui.NewStyle(15)
+        tui.header.TextStyle = ui.NewStyle(15)
 
-        tui.password.TextStyle = ui.NewStyle(ColorLightGreen)
-        tui.password.BorderStyle = ui.NewStyle(ui.ColorGreen)
-        tui.password.TitleStyle = ui.NewStyle(ColorLightGreen)
+        for _, p := range []*prompt.Prompt{tui.passwordPrompt, tui.identityPrompt, tui.certPrompt} {
+            p.TextStyle = ui.NewStyle(ColorLightGreen)
+            p.BorderStyle = ui.NewStyle(ui.ColorGreen)
+            p.TitleStyle = ui.NewStyle(ColorLightGreen)
+        }
     } else if scheme == lightScheme {
         tui.list.BorderStyle = ui.NewStyle(ui.ColorWhite)
         tui.list.TitleStyle = ui.NewStyle(ui.ColorBlack, ColorLightWhite)
@@ -376,10 +592,13 @@ func newTui(scheme ColorScheme) *Tui {
         tui.list.SelectedRowStyle = ui.NewStyle(ui.ColorWhite, ui.ColorBlack)
 
         tui.help.TextStyle = ui.NewStyle(ui.ColorBlack)
+        tui.header.TextStyle = ui.NewStyle(ui.ColorBlack)
 
-        tui.password.TextStyle = ui.NewStyle(ColorLightGreen)
-        tui.password.BorderStyle = ui.NewStyle(ui.ColorGreen)
-        tui.password.TitleStyle = ui.NewStyle(ColorLightGreen)
+        for _, p := range []*prompt.Prompt{tui.passwordPrompt, tui.identityPrompt, tui.certPrompt} {
+            p.TextStyle = ui.NewStyle(ColorLightGreen)
+            p.BorderStyle = ui.NewStyle(ui.ColorGreen)
+            p.TitleStyle = ui.NewStyle(ColorLightGreen)
+        }
     }
 
     return tui
@@ -389,14 +608,14 @@ func resizeTui(tui *Tui) {
     uiW, uiH := ui.TerminalDimensions()
 
     listW := uiW
-    listH := uiH - 3
+    listH := uiH - 4
 
     if listW > 80 {
         listW = 80
     }
 
-    if listH > 25 {
-        listH = 25
+    if listH > 24 {
+        listH = 24
     }
 
     // store width for rows padding
@@ -405,15 +624,20 @@ func resizeTui(tui *Tui) {
     x := (uiW - listW) / 2
     y := (uiH - listH) / 2
 
+    tui.header.SetRect(x, y - 1, x + listW, y)
+
     tui.list.SetRect(x, y, x + listW, y + listH)
 
     tui.help.SetRect(x, y + listH + 1, x + listW, y + listH + 2)
 
-    passwordW := listW * 3 / 4
-    passwordH := 3
-    x = (uiW - passwordW) / 2
-    y = (uiH - passwordH) / 2
-    tui.password.SetRect(x, y, x + passwordW, y + passwordH)
+    // tall enough for the buffer line plus an inline validation error
+    promptW := listW * 3 / 4
+    promptH := 4
+    x = (uiW - promptW) / 2
+    y = (uiH - promptH) / 2
+    tui.passwordPrompt.SetRect(x, y, x + promptW, y + promptH)
+    tui.identityPrompt.SetRect(x, y, x + promptW, y + promptH)
+    tui.certPrompt.SetRect(x, y, x + promptW, y + promptH)
 }
 
 func main() {
@@ -421,21 +645,40 @@ func main() {
     var wpaConfFile string
     var restartNetwork bool
     var useLightTheme bool
+    var backend string
+    var noLock bool
 
     flag.StringVar(&iface, "i", "wlan0", "wireless interface")
     flag.StringVar(&wpaConfFile, "f", "/etc/wpa_supplicant.conf", "path to wpa_supplicant.conf")
-    flag.BoolVar(&restartNetwork, "r", false, "restart netif service if config has changed")
+    flag.BoolVar(&restartNetwork, "r", false, "apply config if it has changed, restarting netif service if a graceful reload isn't available")
     flag.BoolVar(&useLightTheme, "l", false, "use light color scheme")
+    flag.StringVar(&backend, "b", "", "scan backend to use (default: autodetect for this OS)")
+    flag.StringVar(&backend, "backend", "", "scan backend to use (default: autodetect for this OS)")
+    flag.BoolVar(&noLock, "no-lock", false, "don't flock() the config file before writing (for filesystems without flock support)")
 
     flag.Parse()
 
-    networks := listScan(iface)
+    scanner, err := NewScanner(backend)
+    if err != nil {
+        log.Fatal(err)
+    }
+
+    networks, err := scanner.Scan(iface)
+    if err != nil {
+        log.Fatal(err)
+    }
+    scannedAt := time.Now()
+    for i := range networks {
+        networks[i].lastSeen = scannedAt
+    }
+    sortNetworks(networks, sortSSID)
 
     // load known networks from the config file
     knownNetworks, err := loadConfFile(wpaConfFile)
     if err != nil {
         log.Fatal(err)
     }
+    initialKnownNetworks := append([]Network{}, knownNetworks...)
 
     // Initialize and procede with UI
     if err := ui.Init(); err != nil {
@@ -445,12 +688,17 @@ func main() {
     // save edited known networks on exit
     defer func() {
         ui.Close()
-        haveNewConfig := updateConfFile(wpaConfFile, knownNetworks)
+        haveNewConfig := updateConfFile(wpaConfFile, knownNetworks, !noLock)
         if haveNewConfig {
             if restartNetwork {
-                fmt.Println ("new config, restarting network...")
-                cmd := exec.Command("service", "netif", "restart", iface)
-                cmd.Run()
+                reloader := NewReloader()
+                if err := reloader.Reload(iface, initialKnownNetworks, knownNetworks); err != nil {
+                    fmt.Println ("could not apply config without restarting, restarting network...")
+                    cmd := exec.Command("service", "netif", "restart", iface)
+                    cmd.Run()
+                } else {
+                    fmt.Println ("new config applied without restarting network")
+                }
             } else {
                 fmt.Printf ("new config, please run \"service netif restart %s\" manually\n", iface)
             }
@@ -467,11 +715,15 @@ func main() {
     resizeTui(tui)
     updateTui(tui, networks, knownNetworks)
 
+    ui.Render(tui.header)
     ui.Render(tui.list)
     ui.Render(tui.help)
 
     passwordPromptVisible := false
-    password := ""
+    identityPromptVisible := false
+    certPromptVisible := false
+    useCertAuth := false
+    var pendingNet Network
     uiEvents := ui.PollEvents()
     for {
         e := <-uiEvents
@@ -479,39 +731,76 @@ func main() {
             ui.Clear()
             resizeTui(tui)
             updateTui(tui, networks, knownNetworks)
+            ui.Render(tui.header)
             ui.Render(tui.list)
             ui.Render(tui.help)
-            if passwordPromptVisible {
-                ui.Render(tui.password)
+            if identityPromptVisible {
+                ui.Render(tui.identityPrompt)
+            } else if passwordPromptVisible {
+                ui.Render(tui.passwordPrompt)
+            } else if certPromptVisible {
+                ui.Render(tui.certPrompt)
             }
             continue
         }
 
-        if passwordPromptVisible {
-            if len(e.ID) == 1 {
-                password = password + e.ID
-            } else {
-                switch e.ID {
-                case "<Enter>":
-                    // update or add new network
-                    selectedNet := networks[tui.list.SelectedRow]
-                    selectedNet.psk = password
-                    knownNetworks = addNetwork(knownNetworks, selectedNet)
-                    updateTui(tui, networks, knownNetworks)
-                    passwordPromptVisible = false
-                    password = ""
-                case "<Escape>", "<C-c>":
-                    passwordPromptVisible = false
-                    password = ""
-                case "<C-u>":
-                    password = ""
-                case "<Backspace>", "<C-<Backspace>>":
-                    password = password[:len(password)-1]
-                case "<Space>":
-                    password = password + " "
+        if identityPromptVisible {
+            // <Tab> toggles whether the Enterprise flow that follows
+            // asks for a password or a CA cert path (the request asked
+            // for "username + password, or username + cert path").
+            if e.ID == "<Tab>" {
+                useCertAuth = !useCertAuth
+                tui.identityPrompt.Title = identityPromptTitle(useCertAuth)
+                ui.Render(tui.identityPrompt)
+                continue
+            }
+            switch tui.identityPrompt.HandleEvent(e.ID) {
+            case prompt.Accepted:
+                pendingNet.identity = tui.identityPrompt.Value()
+                identityPromptVisible = false
+                tui.identityPrompt.Reset()
+                tui.identityPrompt.Title = identityPromptTitle(false)
+                if useCertAuth {
+                    certPromptVisible = true
+                    tui.certPrompt.Reset()
+                } else {
+                    passwordPromptVisible = true
+                    tui.passwordPrompt.Reset()
                 }
+            case prompt.Cancelled:
+                identityPromptVisible = false
+                tui.identityPrompt.Reset()
+                tui.identityPrompt.Title = identityPromptTitle(false)
+            }
+        } else if passwordPromptVisible {
+            switch tui.passwordPrompt.HandleEvent(e.ID) {
+            case prompt.Accepted:
+                // update or add new network
+                if pendingNet.authType == AuthWPAEnterprise {
+                    pendingNet.eapPassword = tui.passwordPrompt.Value()
+                } else {
+                    pendingNet.psk = tui.passwordPrompt.Value()
+                }
+                knownNetworks = addNetwork(knownNetworks, pendingNet)
+                updateTui(tui, networks, knownNetworks)
+                passwordPromptVisible = false
+                tui.passwordPrompt.Reset()
+            case prompt.Cancelled:
+                passwordPromptVisible = false
+                tui.passwordPrompt.Reset()
+            }
+        } else if certPromptVisible {
+            switch tui.certPrompt.HandleEvent(e.ID) {
+            case prompt.Accepted:
+                pendingNet.caCert = tui.certPrompt.Value()
+                knownNetworks = addNetwork(knownNetworks, pendingNet)
+                updateTui(tui, networks, knownNetworks)
+                certPromptVisible = false
+                tui.certPrompt.Reset()
+            case prompt.Cancelled:
+                certPromptVisible = false
+                tui.certPrompt.Reset()
             }
-            tui.password.Text = password
         } else {
             switch e.ID {
             case "q", "<C-c>":
@@ -532,9 +821,24 @@ func main() {
                 tui.list.ScrollTop()
             case "<End>":
                 tui.list.ScrollBottom()
+            case "s":
+                tui.sortKey = nextSortKey(tui.sortKey)
+                sortNetworks(networks, tui.sortKey)
+                updateTui(tui, networks, knownNetworks)
             case "a":
                 selectedNet := networks[tui.list.SelectedRow]
-                if selectedNet.security {
+                pendingNet = selectedNet
+                if selectedNet.authType == AuthWPAEnterprise {
+                    tui.passwordPrompt.Validate = nil
+                    useCertAuth = false
+                    tui.identityPrompt.Title = identityPromptTitle(useCertAuth)
+                    identityPromptVisible = true
+                } else if selectedNet.authType.needsPassphrase() {
+                    if selectedNet.authType == AuthWPAPersonal || selectedNet.authType == AuthWPA3SAE {
+                        tui.passwordPrompt.Validate = validatePSK
+                    } else {
+                        tui.passwordPrompt.Validate = nil
+                    }
                     passwordPromptVisible = true
                 } else {
                     knownNetworks = addNetwork(knownNetworks, selectedNet)
@@ -552,9 +856,14 @@ func main() {
             }
         }
 
-        if passwordPromptVisible {
-            ui.Render(tui.password)
+        if identityPromptVisible {
+            ui.Render(tui.identityPrompt)
+        } else if passwordPromptVisible {
+            ui.Render(tui.passwordPrompt)
+        } else if certPromptVisible {
+            ui.Render(tui.certPrompt)
         } else {
+            ui.Render(tui.header)
             ui.Render(tui.list)
         }
     }
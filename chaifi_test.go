@@ -0,0 +1,84 @@
+//
+//  Copyright (c) 2020 Oleksandr Tymoshenko <gonzo@bluezbox.com>
+//
+//  Redistribution and use in source and binary forms, with or without
+//  modification, are permitted provided that the following conditions
+//  are met:
+//  1. Redistributions of source code must retain the above copyright
+//     notice unmodified, this list of conditions, and the following
+//     disclaimer.
+//  2. Redistributions in binary form must reproduce the above copyright
+//     notice, this list of conditions and the following disclaimer in the
+//     documentation and/or other materials provided with the distribution.
+//
+//  THIS SOFTWARE IS PROVIDED BY THE AUTHOR AND CONTRIBUTORS ``AS IS'' AND
+//  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+//  IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+//  ARE DISCLAIMED.  IN NO EVENT SHALL THE AUTHOR OR CONTRIBUTORS BE LIABLE
+//  FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+//  DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS
+//  OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+//  HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT
+//  LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY
+//  OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF
+//  SUCH DAMAGE.
+//
+
+package main
+
+import (
+    "testing"
+    "time"
+)
+
+func TestRssiColor(t *testing.T) {
+    cases := []struct {
+        rssi int
+        want string
+    }{
+        {-50, "green"},
+        {-60, "green"},
+        {-65, "yellow"},
+        {-75, "yellow"},
+        {-80, "red"},
+    }
+    for _, c := range cases {
+        if got := rssiColor(c.rssi); got != c.want {
+            t.Errorf("rssiColor(%d) = %q, want %q", c.rssi, got, c.want)
+        }
+    }
+}
+
+func TestFormatRSSIBlankWhenUnknown(t *testing.T) {
+    if got := formatRSSI(0); got != "       " {
+        t.Errorf("formatRSSI(0) = %q, want 7 spaces", got)
+    }
+}
+
+func TestSortNetworks(t *testing.T) {
+    now := time.Unix(1000, 0)
+    networks := []Network{
+        {ssid: "b", rssi: -70, channel: 6, lastSeen: now},
+        {ssid: "a", rssi: -40, channel: 1, lastSeen: now.Add(time.Minute)},
+    }
+
+    sortNetworks(networks, sortRSSI)
+    if networks[0].ssid != "a" {
+        t.Fatalf("sortRSSI: got %q first, want strongest signal (\"a\") first", networks[0].ssid)
+    }
+
+    sortNetworks(networks, sortChannel)
+    if networks[0].ssid != "a" {
+        t.Fatalf("sortChannel: got %q first, want lowest channel (\"a\") first", networks[0].ssid)
+    }
+
+    sortNetworks(networks, sortLastSeen)
+    if networks[0].ssid != "a" {
+        t.Fatalf("sortLastSeen: got %q first, want most recent (\"a\") first", networks[0].ssid)
+    }
+
+    sortNetworks(networks, sortSSID)
+    if networks[0].ssid != "a" {
+        t.Fatalf("sortSSID: got %q first, want \"a\" first", networks[0].ssid)
+    }
+}
@@ -0,0 +1,112 @@
+//
+//  Copyright (c) 2020 Oleksandr Tymoshenko <gonzo@bluezbox.com>
+//
+//  Redistribution and use in source and binary forms, with or without
+//  modification, are permitted provided that the following conditions
+//  are met:
+//  1. Redistributions of source code must retain the above copyright
+//     notice unmodified, this list of conditions, and the following
+//     disclaimer.
+//  2. Redistributions in binary form must reproduce the above copyright
+//     notice, this list of conditions and the following disclaimer in the
+//     documentation and/or other materials provided with the distribution.
+//
+//  THIS SOFTWARE IS PROVIDED BY THE AUTHOR AND CONTRIBUTORS ``AS IS'' AND
+//  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+//  IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+//  ARE DISCLAIMED.  IN NO EVENT SHALL THE AUTHOR OR CONTRIBUTORS BE LIABLE
+//  FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+//  DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS
+//  OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+//  HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT
+//  LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY
+//  OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF
+//  SUCH DAMAGE.
+//
+
+package main
+
+import (
+    "os"
+    "path/filepath"
+    "testing"
+)
+
+// roundTrip writes networks through genNetworkEntry into a minimal conf
+// file and reads them back through loadConfFile.
+func roundTrip(t *testing.T, networks []Network) []Network {
+    t.Helper()
+
+    content := chaifiMarker + "\n"
+    for _, net := range networks {
+        content += genNetworkEntry(net) + "\n"
+    }
+
+    path := filepath.Join(t.TempDir(), "wpa_supplicant.conf")
+    if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+        t.Fatalf("write conf: %v", err)
+    }
+
+    got, err := loadConfFile(path)
+    if err != nil {
+        t.Fatalf("loadConfFile: %v", err)
+    }
+    return got
+}
+
+func TestGenNetworkEntryRoundTrip(t *testing.T) {
+    cases := []Network{
+        {ssid: "open-net", authType: AuthOpen},
+        {ssid: "wep-net", authType: AuthWEP, psk: "0123456789"},
+        {ssid: "home", authType: AuthWPAPersonal, psk: "hunter2hunter2"},
+        {ssid: "home6", authType: AuthWPA3SAE, psk: "hunter2hunter2"},
+        {ssid: "owe-net", authType: AuthOWE},
+        {
+            ssid:        "office",
+            authType:    AuthWPAEnterprise,
+            eapMethod:   "TLS",
+            identity:    "alice",
+            eapPassword: "s3cret",
+            caCert:      "/etc/ssl/corp-ca.pem",
+            phase2:      "auth=MSCHAPV2",
+        },
+    }
+
+    got := roundTrip(t, cases)
+    if len(got) != len(cases) {
+        t.Fatalf("got %d networks, want %d", len(got), len(cases))
+    }
+
+    for i, want := range cases {
+        if got[i].ssid != want.ssid {
+            t.Errorf("case %d: ssid = %q, want %q", i, got[i].ssid, want.ssid)
+        }
+        if got[i].authType != want.authType {
+            t.Errorf("case %d (%s): authType = %v, want %v", i, want.ssid, got[i].authType, want.authType)
+        }
+    }
+
+    office := got[len(got)-1]
+    if office.identity != "alice" || office.eapPassword != "s3cret" || office.caCert != "/etc/ssl/corp-ca.pem" || office.phase2 != "auth=MSCHAPV2" {
+        t.Errorf("office round trip = %+v, want identity/eapPassword/caCert/phase2 preserved", office)
+    }
+}
+
+func TestValidatePSK(t *testing.T) {
+    cases := []struct {
+        psk  string
+        want bool
+    }{
+        {"short", false},
+        {"eightchr", true},
+        {"0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdefa", false}, // 65 hex chars, too long
+        {"0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef", true},   // 64 hex chars
+        {"xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx", false},  // 64 chars but not hex
+    }
+    for _, c := range cases {
+        err := validatePSK(c.psk)
+        if (err == nil) != c.want {
+            t.Errorf("validatePSK(%q) err = %v, want valid=%v", c.psk, err, c.want)
+        }
+    }
+}
@@ -0,0 +1,57 @@
+//
+//  Copyright (c) 2020 Oleksandr Tymoshenko <gonzo@bluezbox.com>
+//
+//  Redistribution and use in source and binary forms, with or without
+//  modification, are permitted provided that the following conditions
+//  are met:
+//  1. Redistributions of source code must retain the above copyright
+//     notice unmodified, this list of conditions, and the following
+//     disclaimer.
+//  2. Redistributions in binary form must reproduce the above copyright
+//     notice, this list of conditions and the following disclaimer in the
+//     documentation and/or other materials provided with the distribution.
+//
+//  THIS SOFTWARE IS PROVIDED BY THE AUTHOR AND CONTRIBUTORS ``AS IS'' AND
+//  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+//  IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+//  ARE DISCLAIMED.  IN NO EVENT SHALL THE AUTHOR OR CONTRIBUTORS BE LIABLE
+//  FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+//  DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS
+//  OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+//  HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT
+//  LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY
+//  OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF
+//  SUCH DAMAGE.
+//
+
+package tui
+
+import (
+    "fmt"
+    "os/exec"
+    "strings"
+)
+
+// clipboardCmds lists the CLI fallbacks tried by Paste, in order. Linux
+// systems typically have xsel or xclip; macOS ships pbpaste.
+var clipboardCmds = [][]string{
+    {"xsel", "--clipboard", "--output"},
+    {"xclip", "-selection", "clipboard", "-o"},
+    {"pbpaste"},
+}
+
+// Paste returns the current OS clipboard contents, trying each known CLI
+// utility in turn. golang.design/x/clipboard would avoid the shell-out
+// but pulls in cgo and platform build constraints chaifi doesn't
+// otherwise need.
+func Paste() (string, error) {
+    var lastErr error
+    for _, args := range clipboardCmds {
+        out, err := exec.Command(args[0], args[1:]...).Output()
+        if err == nil {
+            return strings.TrimRight(string(out), "\n"), nil
+        }
+        lastErr = err
+    }
+    return "", fmt.Errorf("no clipboard utility found (tried xsel, xclip, pbpaste): %w", lastErr)
+}
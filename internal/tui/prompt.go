@@ -0,0 +1,191 @@
+//
+//  Copyright (c) 2020 Oleksandr Tymoshenko <gonzo@bluezbox.com>
+//
+//  Redistribution and use in source and binary forms, with or without
+//  modification, are permitted provided that the following conditions
+//  are met:
+//  1. Redistributions of source code must retain the above copyright
+//     notice unmodified, this list of conditions, and the following
+//     disclaimer.
+//  2. Redistributions in binary form must reproduce the above copyright
+//     notice, this list of conditions and the following disclaimer in the
+//     documentation and/or other materials provided with the distribution.
+//
+//  THIS SOFTWARE IS PROVIDED BY THE AUTHOR AND CONTRIBUTORS ``AS IS'' AND
+//  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+//  IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+//  ARE DISCLAIMED.  IN NO EVENT SHALL THE AUTHOR OR CONTRIBUTORS BE LIABLE
+//  FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+//  DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS
+//  OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+//  HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT
+//  LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY
+//  OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF
+//  SUCH DAMAGE.
+//
+
+// Package tui holds reusable termui widgets shared by chaifi's main UI.
+package tui
+
+import (
+    "fmt"
+    "strings"
+
+    "github.com/gizak/termui/v3/widgets"
+)
+
+// Result is what HandleEvent did with an event.
+type Result int
+
+const (
+    // Continue means the prompt is still being edited.
+    Continue Result = iota
+    // Accepted means <Enter> was pressed and Validate (if set) passed.
+    Accepted
+    // Cancelled means <Escape>/<C-c> was pressed.
+    Cancelled
+)
+
+// Prompt is a single-line text input box with an editable cursor, optional
+// masking (for passwords) and optional inline validation shown in red
+// below the buffer. Embedding *widgets.Paragraph lets a Prompt be passed
+// directly to ui.Render and SetRect like any other termui widget.
+type Prompt struct {
+    *widgets.Paragraph
+    Masked bool
+    // Validate, if set, is called on <Enter>; a non-nil error is shown
+    // inline and the prompt stays open instead of accepting.
+    Validate func(string) error
+
+    buffer []rune
+    cursor int
+    err string
+}
+
+// New creates a Prompt with the given title and border, matching the
+// look of chaifi's other termui widgets.
+func New(title string, masked bool) *Prompt {
+    p := &Prompt{Paragraph: widgets.NewParagraph()}
+    p.Title = title
+    p.Border = true
+    p.Masked = masked
+    p.render()
+    return p
+}
+
+// Value returns the current buffer contents.
+func (p *Prompt) Value() string {
+    return string(p.buffer)
+}
+
+// Reset clears the buffer, cursor and any validation error.
+func (p *Prompt) Reset() {
+    p.buffer = nil
+    p.cursor = 0
+    p.err = ""
+    p.render()
+}
+
+// HandleEvent feeds a termui event ID (as delivered by ui.PollEvents) to
+// the prompt and returns what happened.
+func (p *Prompt) HandleEvent(id string) Result {
+    switch id {
+    case "<Enter>":
+        if p.Validate != nil {
+            if err := p.Validate(p.Value()); err != nil {
+                p.err = err.Error()
+                p.render()
+                return Continue
+            }
+        }
+        return Accepted
+    case "<Escape>", "<C-c>":
+        return Cancelled
+    case "<C-u>":
+        p.buffer = nil
+        p.cursor = 0
+    case "<C-w>":
+        p.deleteWord()
+    case "<Backspace>", "<C-<Backspace>>":
+        p.deleteBackward()
+    case "<Left>":
+        if p.cursor > 0 {
+            p.cursor--
+        }
+    case "<Right>":
+        if p.cursor < len(p.buffer) {
+            p.cursor++
+        }
+    case "<Home>":
+        p.cursor = 0
+    case "<End>":
+        p.cursor = len(p.buffer)
+    case "<Space>":
+        p.insert(' ')
+    case "<C-v>":
+        if text, err := Paste(); err == nil {
+            for _, r := range text {
+                p.insert(r)
+            }
+        }
+    default:
+        runes := []rune(id)
+        if len(runes) == 1 {
+            p.insert(runes[0])
+        } else {
+            // unrecognized multi-rune event (e.g. another control
+            // sequence); nothing to edit, just redraw as-is.
+            p.render()
+            return Continue
+        }
+    }
+
+    p.err = ""
+    p.render()
+    return Continue
+}
+
+// insert adds r at the cursor position and advances the cursor past it.
+func (p *Prompt) insert(r rune) {
+    p.buffer = append(p.buffer[:p.cursor], append([]rune{r}, p.buffer[p.cursor:]...)...)
+    p.cursor++
+}
+
+// deleteBackward removes the rune before the cursor, if any. Guarding on
+// an empty buffer is what fixes the crash the old ad-hoc state machine
+// had on <Backspace> with nothing typed yet.
+func (p *Prompt) deleteBackward() {
+    if p.cursor == 0 {
+        return
+    }
+    p.buffer = append(p.buffer[:p.cursor-1], p.buffer[p.cursor:]...)
+    p.cursor--
+}
+
+// deleteWord implements Ctrl-W: delete the run of non-space characters
+// (and any trailing spaces) immediately before the cursor.
+func (p *Prompt) deleteWord() {
+    end := p.cursor
+    start := end
+    for start > 0 && p.buffer[start-1] == ' ' {
+        start--
+    }
+    for start > 0 && p.buffer[start-1] != ' ' {
+        start--
+    }
+    p.buffer = append(p.buffer[:start], p.buffer[end:]...)
+    p.cursor = start
+}
+
+// render refreshes the Paragraph's Text from the buffer, masking it and
+// appending the validation error (if any) on its own line.
+func (p *Prompt) render() {
+    text := string(p.buffer)
+    if p.Masked {
+        text = strings.Repeat("•", len(p.buffer))
+    }
+    if p.err != "" {
+        text = fmt.Sprintf("%s\n[%s](fg:red)", text, p.err)
+    }
+    p.Text = text
+}
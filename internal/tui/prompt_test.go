@@ -0,0 +1,102 @@
+//
+//  Copyright (c) 2020 Oleksandr Tymoshenko <gonzo@bluezbox.com>
+//
+//  Redistribution and use in source and binary forms, with or without
+//  modification, are permitted provided that the following conditions
+//  are met:
+//  1. Redistributions of source code must retain the above copyright
+//     notice unmodified, this list of conditions, and the following
+//     disclaimer.
+//  2. Redistributions in binary form must reproduce the above copyright
+//     notice, this list of conditions and the following disclaimer in the
+//     documentation and/or other materials provided with the distribution.
+//
+//  THIS SOFTWARE IS PROVIDED BY THE AUTHOR AND CONTRIBUTORS ``AS IS'' AND
+//  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+//  IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+//  ARE DISCLAIMED.  IN NO EVENT SHALL THE AUTHOR OR CONTRIBUTORS BE LIABLE
+//  FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+//  DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS
+//  OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+//  HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT
+//  LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY
+//  OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF
+//  SUCH DAMAGE.
+//
+
+package tui
+
+import (
+    "errors"
+    "testing"
+)
+
+func TestPromptBackspaceOnEmptyBufferDoesNotPanic(t *testing.T) {
+    p := New("[ Test ]", false)
+    defer func() {
+        if r := recover(); r != nil {
+            t.Fatalf("HandleEvent(<Backspace>) on empty buffer panicked: %v", r)
+        }
+    }()
+    p.HandleEvent("<Backspace>")
+    if p.Value() != "" {
+        t.Errorf("Value() = %q, want empty", p.Value())
+    }
+}
+
+func TestPromptInsertAndBackspace(t *testing.T) {
+    p := New("[ Test ]", false)
+    for _, r := range "hello" {
+        p.HandleEvent(string(r))
+    }
+    if p.Value() != "hello" {
+        t.Fatalf("Value() = %q, want hello", p.Value())
+    }
+    p.HandleEvent("<Backspace>")
+    if p.Value() != "hell" {
+        t.Fatalf("Value() = %q, want hell", p.Value())
+    }
+}
+
+func TestPromptDeleteWord(t *testing.T) {
+    p := New("[ Test ]", false)
+    for _, r := range "hello world" {
+        p.HandleEvent(string(r))
+    }
+    p.HandleEvent("<C-w>")
+    if p.Value() != "hello " {
+        t.Fatalf("Value() = %q, want \"hello \"", p.Value())
+    }
+}
+
+func TestPromptValidateBlocksAccept(t *testing.T) {
+    p := New("[ Test ]", false)
+    p.Validate = func(s string) error {
+        if len(s) < 8 {
+            return errors.New("too short")
+        }
+        return nil
+    }
+    for _, r := range "short" {
+        p.HandleEvent(string(r))
+    }
+    if result := p.HandleEvent("<Enter>"); result != Continue {
+        t.Fatalf("HandleEvent(<Enter>) = %v, want Continue when Validate fails", result)
+    }
+
+    for _, r := range "ishortlong" {
+        p.HandleEvent(string(r))
+    }
+    if result := p.HandleEvent("<Enter>"); result != Accepted {
+        t.Fatalf("HandleEvent(<Enter>) = %v, want Accepted once Validate passes", result)
+    }
+}
+
+func TestPromptMaskedRendersDots(t *testing.T) {
+    p := New("[ Password ]", true)
+    p.HandleEvent("a")
+    p.HandleEvent("b")
+    if p.Text != "••" {
+        t.Fatalf("Text = %q, want two masking dots", p.Text)
+    }
+}
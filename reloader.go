@@ -0,0 +1,78 @@
+//
+//  Copyright (c) 2020 Oleksandr Tymoshenko <gonzo@bluezbox.com>
+//
+//  Redistribution and use in source and binary forms, with or without
+//  modification, are permitted provided that the following conditions
+//  are met:
+//  1. Redistributions of source code must retain the above copyright
+//     notice unmodified, this list of conditions, and the following
+//     disclaimer.
+//  2. Redistributions in binary form must reproduce the above copyright
+//     notice, this list of conditions and the following disclaimer in the
+//     documentation and/or other materials provided with the distribution.
+//
+//  THIS SOFTWARE IS PROVIDED BY THE AUTHOR AND CONTRIBUTORS ``AS IS'' AND
+//  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+//  IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+//  ARE DISCLAIMED.  IN NO EVENT SHALL THE AUTHOR OR CONTRIBUTORS BE LIABLE
+//  FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+//  DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS
+//  OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+//  HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT
+//  LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY
+//  OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF
+//  SUCH DAMAGE.
+//
+
+package main
+
+// Reloader applies a new set of known networks to a running
+// wpa_supplicant without tearing down the interface the way
+// `service netif restart` does. Every supported OS ships its own
+// implementation, picked by newPlatformReloader (one per
+// reloader_<goos>.go).
+type Reloader interface {
+    // Reload applies the change from previous to knownNetworks to the
+    // running supplicant for iface. It returns an
+    // error if no graceful control channel is available, in which case
+    // the caller should fall back to restarting the interface.
+    Reload(iface string, previous, knownNetworks []Network) error
+}
+
+// NewReloader returns the Reloader for the current platform.
+func NewReloader() Reloader {
+    return newPlatformReloader()
+}
+
+// addedNetworks returns the entries in knownNetworks whose SSID isn't
+// present in previous — the ones Reload needs to push to the supplicant.
+func addedNetworks(previous, knownNetworks []Network) []Network {
+    seen := map[string]bool{}
+    for _, n := range previous {
+        seen[n.ssid] = true
+    }
+    added := []Network{}
+    for _, n := range knownNetworks {
+        if !seen[n.ssid] {
+            added = append(added, n)
+        }
+    }
+    return added
+}
+
+// removedNetworks returns the entries in previous whose SSID is no
+// longer present in knownNetworks — the ones Reload needs to remove
+// from the supplicant.
+func removedNetworks(previous, knownNetworks []Network) []Network {
+    seen := map[string]bool{}
+    for _, n := range knownNetworks {
+        seen[n.ssid] = true
+    }
+    removed := []Network{}
+    for _, n := range previous {
+        if !seen[n.ssid] {
+            removed = append(removed, n)
+        }
+    }
+    return removed
+}
@@ -0,0 +1,168 @@
+// +build linux
+
+//
+//  Copyright (c) 2020 Oleksandr Tymoshenko <gonzo@bluezbox.com>
+//
+//  Redistribution and use in source and binary forms, with or without
+//  modification, are permitted provided that the following conditions
+//  are met:
+//  1. Redistributions of source code must retain the above copyright
+//     notice unmodified, this list of conditions, and the following
+//     disclaimer.
+//  2. Redistributions in binary form must reproduce the above copyright
+//     notice, this list of conditions and the following disclaimer in the
+//     documentation and/or other materials provided with the distribution.
+//
+//  THIS SOFTWARE IS PROVIDED BY THE AUTHOR AND CONTRIBUTORS ``AS IS'' AND
+//  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+//  IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+//  ARE DISCLAIMED.  IN NO EVENT SHALL THE AUTHOR OR CONTRIBUTORS BE LIABLE
+//  FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+//  DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS
+//  OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+//  HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT
+//  LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY
+//  OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF
+//  SUCH DAMAGE.
+//
+
+package main
+
+import (
+    "fmt"
+    "strings"
+
+    "github.com/godbus/dbus/v5"
+)
+
+const wpaSupplicantDest = "fi.w1.wpa_supplicant1"
+const wpaSupplicantPath = "/fi/w1/wpa_supplicant1"
+
+func newPlatformReloader() Reloader {
+    return dbusReloader{}
+}
+
+// dbusReloader talks to wpa_supplicant's D-Bus API directly, so newly
+// added networks take effect without restarting the interface.
+type dbusReloader struct{}
+
+func (dbusReloader) Reload(iface string, previous, knownNetworks []Network) error {
+    conn, err := dbus.SystemBus()
+    if err != nil {
+        return fmt.Errorf("connect to system bus: %w", err)
+    }
+    defer conn.Close()
+
+    root := conn.Object(wpaSupplicantDest, dbus.ObjectPath(wpaSupplicantPath))
+
+    var ifacePath dbus.ObjectPath
+    if err := root.Call(wpaSupplicantDest+".GetInterface", 0, iface).Store(&ifacePath); err != nil {
+        return fmt.Errorf("get wpa_supplicant interface %q: %w", iface, err)
+    }
+
+    ifaceDest := wpaSupplicantDest + ".Interface"
+    ifaceObj := conn.Object(wpaSupplicantDest, ifacePath)
+
+    for _, net := range addedNetworks(previous, knownNetworks) {
+        var networkPath dbus.ObjectPath
+        call := ifaceObj.Call(ifaceDest+".AddNetwork", 0, networkProperties(net))
+        if err := call.Store(&networkPath); err != nil {
+            return fmt.Errorf("AddNetwork(%s): %w", net.ssid, err)
+        }
+        // EnableNetwork, not SelectNetwork: SelectNetwork is exclusive
+        // and disables every other configured network, which is the
+        // opposite of a graceful reload.
+        if err := ifaceObj.Call(ifaceDest+".EnableNetwork", 0, networkPath).Err; err != nil {
+            return fmt.Errorf("EnableNetwork(%s): %w", net.ssid, err)
+        }
+    }
+
+    for _, net := range removedNetworks(previous, knownNetworks) {
+        networkPath, err := findNetworkPath(conn, ifaceObj, ifaceDest, net.ssid)
+        if err != nil {
+            return fmt.Errorf("find network %s: %w", net.ssid, err)
+        }
+        if networkPath == "" {
+            continue
+        }
+        if err := ifaceObj.Call(ifaceDest+".RemoveNetwork", 0, networkPath).Err; err != nil {
+            return fmt.Errorf("RemoveNetwork(%s): %w", net.ssid, err)
+        }
+    }
+
+    if err := ifaceObj.Call(ifaceDest+".SaveConfig", 0).Err; err != nil {
+        return fmt.Errorf("SaveConfig: %w", err)
+    }
+
+    return nil
+}
+
+// findNetworkPath walks the interface's configured Networks to find the
+// object whose ssid property matches, so a network removed from the TUI
+// in this session can be located and removed from the running
+// supplicant. Returns "" if no match is found.
+func findNetworkPath(conn *dbus.Conn, ifaceObj dbus.BusObject, ifaceDest, ssid string) (dbus.ObjectPath, error) {
+    networksProp, err := ifaceObj.GetProperty(ifaceDest + ".Networks")
+    if err != nil {
+        return "", fmt.Errorf("get Networks: %w", err)
+    }
+    paths, ok := networksProp.Value().([]dbus.ObjectPath)
+    if !ok {
+        return "", fmt.Errorf("unexpected type for Networks property")
+    }
+
+    networkDest := wpaSupplicantDest + ".Network"
+    for _, path := range paths {
+        netObj := conn.Object(wpaSupplicantDest, path)
+        propsVariant, err := netObj.GetProperty(networkDest + ".Properties")
+        if err != nil {
+            continue
+        }
+        props, ok := propsVariant.Value().(map[string]dbus.Variant)
+        if !ok {
+            continue
+        }
+        netSSID, ok := props["ssid"].Value().(string)
+        if !ok {
+            continue
+        }
+        if strings.Trim(netSSID, `"`) == ssid {
+            return path, nil
+        }
+    }
+    return "", nil
+}
+
+// networkProperties builds the AddNetwork property map with the same
+// key_mgmt (and ieee80211w) genNetworkEntry writes for the same Auth,
+// plus the PSK or the 802.1X identity and password. WEP networks are
+// sent as key_mgmt NONE with no auth_alg or key, and the eap, ca_cert
+// and phase2 fields are not sent.
+func networkProperties(net Network) map[string]dbus.Variant {
+    props := map[string]dbus.Variant{
+        "ssid": dbus.MakeVariant(net.ssid),
+    }
+    switch net.authType {
+    case AuthWPAPersonal:
+        props["key_mgmt"] = dbus.MakeVariant([]string{"WPA-PSK"})
+        props["psk"] = dbus.MakeVariant(net.psk)
+    case AuthWPA3SAE:
+        props["key_mgmt"] = dbus.MakeVariant([]string{"SAE"})
+        props["psk"] = dbus.MakeVariant(net.psk)
+        props["ieee80211w"] = dbus.MakeVariant(uint32(2))
+    case AuthWPAEnterprise:
+        props["key_mgmt"] = dbus.MakeVariant([]string{"WPA-EAP"})
+        if net.identity != "" {
+            props["identity"] = dbus.MakeVariant(net.identity)
+        }
+        if net.eapPassword != "" {
+            props["password"] = dbus.MakeVariant(net.eapPassword)
+        }
+    case AuthOWE:
+        props["key_mgmt"] = dbus.MakeVariant([]string{"OWE"})
+        props["ieee80211w"] = dbus.MakeVariant(uint32(2))
+    default:
+        props["key_mgmt"] = dbus.MakeVariant([]string{"NONE"})
+    }
+    return props
+}
@@ -0,0 +1,59 @@
+// +build linux
+
+//
+//  Copyright (c) 2020 Oleksandr Tymoshenko <gonzo@bluezbox.com>
+//
+//  Redistribution and use in source and binary forms, with or without
+//  modification, are permitted provided that the following conditions
+//  are met:
+//  1. Redistributions of source code must retain the above copyright
+//     notice unmodified, this list of conditions, and the following
+//     disclaimer.
+//  2. Redistributions in binary form must reproduce the above copyright
+//     notice, this list of conditions and the following disclaimer in the
+//     documentation and/or other materials provided with the distribution.
+//
+//  THIS SOFTWARE IS PROVIDED BY THE AUTHOR AND CONTRIBUTORS ``AS IS'' AND
+//  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+//  IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+//  ARE DISCLAIMED.  IN NO EVENT SHALL THE AUTHOR OR CONTRIBUTORS BE LIABLE
+//  FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+//  DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS
+//  OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+//  HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT
+//  LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY
+//  OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF
+//  SUCH DAMAGE.
+//
+
+package main
+
+import "testing"
+
+func TestNetworkPropertiesWPA3SAE(t *testing.T) {
+    props := networkProperties(Network{ssid: "home", authType: AuthWPA3SAE, psk: "hunter2hunter2"})
+
+    keyMgmt, ok := props["key_mgmt"].Value().([]string)
+    if !ok || len(keyMgmt) != 1 || keyMgmt[0] != "SAE" {
+        t.Fatalf("key_mgmt = %v, want [SAE]", props["key_mgmt"])
+    }
+    if mgmtW, ok := props["ieee80211w"].Value().(uint32); !ok || mgmtW != 2 {
+        t.Fatalf("ieee80211w = %v, want 2", props["ieee80211w"])
+    }
+}
+
+func TestNetworkPropertiesEnterprise(t *testing.T) {
+    props := networkProperties(Network{
+        ssid:     "office",
+        authType: AuthWPAEnterprise,
+        identity: "alice",
+    })
+
+    keyMgmt, ok := props["key_mgmt"].Value().([]string)
+    if !ok || len(keyMgmt) != 1 || keyMgmt[0] != "WPA-EAP" {
+        t.Fatalf("key_mgmt = %v, want [WPA-EAP]", props["key_mgmt"])
+    }
+    if _, ok := props["password"]; ok {
+        t.Fatalf("password should be omitted when eapPassword is empty")
+    }
+}
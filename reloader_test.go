@@ -0,0 +1,49 @@
+//
+//  Copyright (c) 2020 Oleksandr Tymoshenko <gonzo@bluezbox.com>
+//
+//  Redistribution and use in source and binary forms, with or without
+//  modification, are permitted provided that the following conditions
+//  are met:
+//  1. Redistributions of source code must retain the above copyright
+//     notice unmodified, this list of conditions, and the following
+//     disclaimer.
+//  2. Redistributions in binary form must reproduce the above copyright
+//     notice, this list of conditions and the following disclaimer in the
+//     documentation and/or other materials provided with the distribution.
+//
+//  THIS SOFTWARE IS PROVIDED BY THE AUTHOR AND CONTRIBUTORS ``AS IS'' AND
+//  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+//  IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+//  ARE DISCLAIMED.  IN NO EVENT SHALL THE AUTHOR OR CONTRIBUTORS BE LIABLE
+//  FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+//  DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS
+//  OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+//  HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT
+//  LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY
+//  OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF
+//  SUCH DAMAGE.
+//
+
+package main
+
+import "testing"
+
+func TestAddedNetworks(t *testing.T) {
+    previous := []Network{{ssid: "kept"}, {ssid: "gone"}}
+    known := []Network{{ssid: "kept"}, {ssid: "new"}}
+
+    added := addedNetworks(previous, known)
+    if len(added) != 1 || added[0].ssid != "new" {
+        t.Fatalf("addedNetworks() = %v, want just \"new\"", added)
+    }
+}
+
+func TestRemovedNetworks(t *testing.T) {
+    previous := []Network{{ssid: "kept"}, {ssid: "gone"}}
+    known := []Network{{ssid: "kept"}, {ssid: "new"}}
+
+    removed := removedNetworks(previous, known)
+    if len(removed) != 1 || removed[0].ssid != "gone" {
+        t.Fatalf("removedNetworks() = %v, want just \"gone\"", removed)
+    }
+}
@@ -0,0 +1,49 @@
+//
+//  Copyright (c) 2020 Oleksandr Tymoshenko <gonzo@bluezbox.com>
+//
+//  Redistribution and use in source and binary forms, with or without
+//  modification, are permitted provided that the following conditions
+//  are met:
+//  1. Redistributions of source code must retain the above copyright
+//     notice unmodified, this list of conditions, and the following
+//     disclaimer.
+//  2. Redistributions in binary form must reproduce the above copyright
+//     notice, this list of conditions and the following disclaimer in the
+//     documentation and/or other materials provided with the distribution.
+//
+//  THIS SOFTWARE IS PROVIDED BY THE AUTHOR AND CONTRIBUTORS ``AS IS'' AND
+//  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+//  IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+//  ARE DISCLAIMED.  IN NO EVENT SHALL THE AUTHOR OR CONTRIBUTORS BE LIABLE
+//  FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+//  DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS
+//  OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+//  HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT
+//  LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY
+//  OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF
+//  SUCH DAMAGE.
+//
+
+package main
+
+import (
+    "runtime"
+)
+
+// Scanner knows how to enumerate visible WiFi networks on a given
+// interface. Every supported OS ships its own implementation backed by
+// whatever scanning tool is native to that platform; newPlatformScanner
+// (one per scanner_<goos>.go) is the only thing that needs to change to
+// add a new one.
+type Scanner interface {
+    Scan(iface string) ([]Network, error)
+}
+
+// NewScanner picks the Scanner for backend, defaulting to the current
+// GOOS when backend is empty so normal startup needs no flag at all.
+func NewScanner(backend string) (Scanner, error) {
+    if backend == "" {
+        backend = runtime.GOOS
+    }
+    return newPlatformScanner(backend)
+}
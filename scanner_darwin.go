@@ -0,0 +1,160 @@
+// +build darwin
+
+//
+//  Copyright (c) 2020 Oleksandr Tymoshenko <gonzo@bluezbox.com>
+//
+//  Redistribution and use in source and binary forms, with or without
+//  modification, are permitted provided that the following conditions
+//  are met:
+//  1. Redistributions of source code must retain the above copyright
+//     notice unmodified, this list of conditions, and the following
+//     disclaimer.
+//  2. Redistributions in binary form must reproduce the above copyright
+//     notice, this list of conditions and the following disclaimer in the
+//     documentation and/or other materials provided with the distribution.
+//
+//  THIS SOFTWARE IS PROVIDED BY THE AUTHOR AND CONTRIBUTORS ``AS IS'' AND
+//  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+//  IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+//  ARE DISCLAIMED.  IN NO EVENT SHALL THE AUTHOR OR CONTRIBUTORS BE LIABLE
+//  FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+//  DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS
+//  OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+//  HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT
+//  LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY
+//  OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF
+//  SUCH DAMAGE.
+//
+
+package main
+
+import (
+    "bufio"
+    "bytes"
+    "fmt"
+    "os/exec"
+    "sort"
+    "strconv"
+    "strings"
+)
+
+const airportPath = "/System/Library/PrivateFrameworks/Apple80211.framework/Versions/Current/Resources/airport"
+
+// newPlatformScanner resolves backend for macOS builds. Apple's airport
+// utility is the only supported way to trigger a scan without shipping
+// a CoreWLAN wrapper.
+func newPlatformScanner(backend string) (Scanner, error) {
+    switch backend {
+    case "darwin", "airport":
+        return airportScanner{}, nil
+    default:
+        return nil, fmt.Errorf("unsupported scan backend %q on darwin", backend)
+    }
+}
+
+// airportScanner shells out to "airport -s" for a scan table.
+type airportScanner struct{}
+
+func (airportScanner) Scan(iface string) ([]Network, error) {
+    cmd := exec.Command(airportPath, "-s")
+    cmdOutput := &bytes.Buffer{}
+    cmd.Stdout = cmdOutput
+    if err := cmd.Run(); err != nil {
+        return nil, err
+    }
+    return parseAirportScan(cmdOutput.String()), nil
+}
+
+// parseAirportScan parses "airport -s" output:
+//   SSID BSSID             RSSI CHANNEL HT CC SECURITY (auth/unicast/group)
+func parseAirportScan(output string) []Network {
+    result := []Network{}
+    scanner := bufio.NewScanner(strings.NewReader(output))
+    first := true
+    for scanner.Scan() {
+        line := scanner.Text()
+        if first {
+            // header row
+            first = false
+            continue
+        }
+        if strings.TrimSpace(line) == "" {
+            continue
+        }
+
+        fields := strings.Fields(line)
+        if len(fields) < 7 {
+            continue
+        }
+
+        // BSSID is a fixed "xx:xx:xx:xx:xx:xx" token; everything before
+        // it (possibly containing spaces) is the SSID.
+        bssidIdx := -1
+        for i, f := range fields {
+            if strings.Count(f, ":") == 5 {
+                bssidIdx = i
+                break
+            }
+        }
+        if bssidIdx <= 0 {
+            continue
+        }
+
+        ssid := strings.Join(fields[:bssidIdx], " ")
+        rest := fields[bssidIdx+1:]
+        if len(rest) < 5 {
+            continue
+        }
+
+        rssi, _ := strconv.Atoi(rest[0])
+        channel, _ := strconv.Atoi(strings.SplitN(rest[1], ",", 2)[0])
+        security := strings.Join(rest[4:], " ")
+
+        network := Network{
+            ssid:     ssid,
+            rssi:     rssi,
+            channel:  channel,
+            authType: authFromSecurityColumn(security),
+            cipher:   cipherFromSecurityColumn(security),
+        }
+        result = append(result, network)
+    }
+
+    sort.Slice(result, func(i, j int) bool {
+        return result[i].ssid < result[j].ssid
+    })
+
+    return result
+}
+
+// cipherFromSecurityColumn pulls the pairwise cipher out of airport -s's
+// SECURITY column, e.g. "WPA2(PSK/AES/AES)" -> "AES".
+func cipherFromSecurityColumn(security string) string {
+    open := strings.Index(security, "(")
+    closeIdx := strings.Index(security, ")")
+    if open < 0 || closeIdx <= open {
+        return ""
+    }
+    parts := strings.Split(security[open+1:closeIdx], "/")
+    if len(parts) < 2 {
+        return ""
+    }
+    return parts[1]
+}
+
+// authFromSecurityColumn classifies airport -s's SECURITY column, e.g.
+// "WPA2(PSK/AES/AES)", "WPA2 Enterprise", "WPA3 Personal", "NONE".
+func authFromSecurityColumn(security string) Auth {
+    switch {
+    case security == "NONE":
+        return AuthOpen
+    case strings.Contains(security, "Enterprise"), strings.Contains(security, "802.1X"):
+        return AuthWPAEnterprise
+    case strings.Contains(security, "WPA3"):
+        return AuthWPA3SAE
+    case strings.Contains(security, "WEP"):
+        return AuthWEP
+    default:
+        return AuthWPAPersonal
+    }
+}
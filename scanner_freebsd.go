@@ -0,0 +1,151 @@
+// +build freebsd
+
+//
+//  Copyright (c) 2020 Oleksandr Tymoshenko <gonzo@bluezbox.com>
+//
+//  Redistribution and use in source and binary forms, with or without
+//  modification, are permitted provided that the following conditions
+//  are met:
+//  1. Redistributions of source code must retain the above copyright
+//     notice unmodified, this list of conditions, and the following
+//     disclaimer.
+//  2. Redistributions in binary form must reproduce the above copyright
+//     notice, this list of conditions and the following disclaimer in the
+//     documentation and/or other materials provided with the distribution.
+//
+//  THIS SOFTWARE IS PROVIDED BY THE AUTHOR AND CONTRIBUTORS ``AS IS'' AND
+//  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+//  IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+//  ARE DISCLAIMED.  IN NO EVENT SHALL THE AUTHOR OR CONTRIBUTORS BE LIABLE
+//  FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+//  DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS
+//  OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+//  HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT
+//  LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY
+//  OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF
+//  SUCH DAMAGE.
+//
+
+package main
+
+import (
+    "bytes"
+    "fmt"
+    "os/exec"
+    "sort"
+    "strings"
+)
+
+// newPlatformScanner resolves backend for FreeBSD builds. "ifconfig" is
+// the only scanning method available here, but it's still named so -b
+// gives a clear error instead of silently picking the wrong OS' backend.
+func newPlatformScanner(backend string) (Scanner, error) {
+    switch backend {
+    case "freebsd", "ifconfig":
+        return ifconfigScanner{}, nil
+    default:
+        return nil, fmt.Errorf("unsupported scan backend %q on freebsd", backend)
+    }
+}
+
+// ifconfigScanner shells out to "ifconfig list scan", the only
+// interface FreeBSD's net80211 stack exposes for a site survey.
+type ifconfigScanner struct{}
+
+func (ifconfigScanner) Scan(iface string) ([]Network, error) {
+    result := []Network{}
+    cmd := exec.Command("ifconfig", "-v", iface, "list", "scan")
+    cmdOutput := &bytes.Buffer{}
+    cmd.Stdout = cmdOutput
+    err := cmd.Run()
+    if err != nil {
+        return nil, err
+    }
+    output := string(cmdOutput.Bytes())
+    lines := strings.Split(output, "\n")
+    if len(lines) < 1 {
+        return result, nil
+    }
+    header := lines[0]
+    lines = lines[1:]
+    ssidEnd := strings.Index(header, "BSSID") - 1
+    if ssidEnd < 0 {
+        return result, nil
+    }
+
+    for _, line := range lines {
+        if len(line) < ssidEnd + 1 {
+            continue
+        }
+        ssid := line[:ssidEnd]
+        ssid = strings.Trim(ssid, " ")
+
+        // For now just skip networks with empty SSID
+        if ssid == "" {
+            continue
+        }
+
+        found := false
+        for _, n := range result {
+            if n.ssid == ssid {
+                found = true
+                break
+            }
+        }
+        if found {
+            continue
+        }
+
+        network := Network{ssid: ssid, authType: detectAuth(line), cipher: cipherFromRSN(line)}
+        result = append(result, network)
+    }
+
+    sort.Slice(result, func(i, j int) bool {
+       return result[i].ssid < result[j].ssid
+    })
+
+    return result, nil
+}
+
+// detectAuth classifies a scan line by the RSN/WPA capability string
+// ifconfig prints, e.g. "RSN<PSK,TKIP/AES,AES-CCM,4-PTKSA,...>". SAE
+// and 802.1X/EAP show up as substrings inside that same bracket.
+func detectAuth(line string) Auth {
+    rsnPos := strings.Index(line, "RSN<")
+    wpaPos := strings.Index(line, "WPA<")
+    if rsnPos < 0 && wpaPos < 0 {
+        return AuthOpen
+    }
+
+    switch {
+    case strings.Contains(line, "SAE"):
+        return AuthWPA3SAE
+    case strings.Contains(line, "802.1X"), strings.Contains(line, "EAP"):
+        return AuthWPAEnterprise
+    default:
+        return AuthWPAPersonal
+    }
+}
+
+// cipherFromRSN pulls the pairwise cipher out of the RSN<...>/WPA<...>
+// token list ifconfig prints, e.g. "RSN<PSK,TKIP/AES,AES-CCM,...>" ->
+// "TKIP/AES".
+func cipherFromRSN(line string) string {
+    for _, tag := range []string{"RSN<", "WPA<"} {
+        start := strings.Index(line, tag)
+        if start < 0 {
+            continue
+        }
+        start += len(tag)
+        end := strings.Index(line[start:], ">")
+        if end < 0 {
+            continue
+        }
+        tokens := strings.Split(line[start:start+end], ",")
+        if len(tokens) > 1 {
+            return tokens[1]
+        }
+        return ""
+    }
+    return ""
+}
@@ -0,0 +1,309 @@
+// +build linux
+
+//
+//  Copyright (c) 2020 Oleksandr Tymoshenko <gonzo@bluezbox.com>
+//
+//  Redistribution and use in source and binary forms, with or without
+//  modification, are permitted provided that the following conditions
+//  are met:
+//  1. Redistributions of source code must retain the above copyright
+//     notice unmodified, this list of conditions, and the following
+//     disclaimer.
+//  2. Redistributions in binary form must reproduce the above copyright
+//     notice, this list of conditions and the following disclaimer in the
+//     documentation and/or other materials provided with the distribution.
+//
+//  THIS SOFTWARE IS PROVIDED BY THE AUTHOR AND CONTRIBUTORS ``AS IS'' AND
+//  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+//  IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+//  ARE DISCLAIMED.  IN NO EVENT SHALL THE AUTHOR OR CONTRIBUTORS BE LIABLE
+//  FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+//  DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS
+//  OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+//  HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT
+//  LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY
+//  OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF
+//  SUCH DAMAGE.
+//
+
+package main
+
+import (
+    "bufio"
+    "bytes"
+    "fmt"
+    "net"
+    "os/exec"
+    "sort"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// newPlatformScanner resolves backend for Linux builds. "iw" shells out
+// to the iw(8) CLI; "wpa_supplicant" talks directly to the running
+// supplicant's control socket, which also works on setups where iw
+// isn't installed. Default to iw since it needs no prior configuration.
+func newPlatformScanner(backend string) (Scanner, error) {
+    switch backend {
+    case "linux", "iw":
+        return iwScanner{}, nil
+    case "wpa_supplicant":
+        return wpaCtrlScanner{}, nil
+    default:
+        return nil, fmt.Errorf("unsupported scan backend %q on linux", backend)
+    }
+}
+
+// iwScanner drives "iw dev <iface> scan", the standard nl80211 CLI.
+type iwScanner struct{}
+
+func (iwScanner) Scan(iface string) ([]Network, error) {
+    cmd := exec.Command("iw", "dev", iface, "scan")
+    cmdOutput := &bytes.Buffer{}
+    cmd.Stdout = cmdOutput
+    if err := cmd.Run(); err != nil {
+        return nil, err
+    }
+    return parseIwScan(cmdOutput.String()), nil
+}
+
+// parseIwScan turns "iw scan" output into Networks. The format is a
+// flat list of "BSS <mac>(...)" blocks each followed by indented
+// "key: value" fields; we only pull what chaifi currently needs.
+func parseIwScan(output string) []Network {
+    result := []Network{}
+    scanner := bufio.NewScanner(strings.NewReader(output))
+    var cur *Network
+    for scanner.Scan() {
+        line := scanner.Text()
+        trimmed := strings.TrimSpace(line)
+        if strings.HasPrefix(line, "BSS ") {
+            if cur != nil {
+                result = append(result, *cur)
+            }
+            cur = &Network{}
+            continue
+        }
+        if cur == nil {
+            continue
+        }
+        // the pairwise cipher is reported on its own indented line
+        // alongside, not instead of, the auth-suite lines below; like its
+        // neighbors it's prefixed with a "* " bullet under RSN/WPA
+        bulleted := strings.TrimPrefix(trimmed, "* ")
+        if strings.HasPrefix(bulleted, "Pairwise ciphers: ") {
+            cur.cipher = strings.TrimPrefix(bulleted, "Pairwise ciphers: ")
+        }
+        switch {
+        case strings.HasPrefix(trimmed, "SSID: "):
+            cur.ssid = strings.TrimPrefix(trimmed, "SSID: ")
+        case strings.HasPrefix(trimmed, "signal: "):
+            fields := strings.Fields(trimmed)
+            if len(fields) >= 2 {
+                if f, err := strconv.ParseFloat(fields[1], 64); err == nil {
+                    cur.rssi = int(f)
+                }
+            }
+        case strings.HasPrefix(trimmed, "freq: "):
+            if f, err := strconv.Atoi(strings.TrimPrefix(trimmed, "freq: ")); err == nil {
+                cur.frequency = f
+                cur.channel = freqToChannel(f)
+            }
+        case strings.HasPrefix(trimmed, "RSN:"), strings.HasPrefix(trimmed, "WPA:"):
+            if cur.authType == AuthOpen {
+                cur.authType = AuthWPAPersonal
+            }
+        case strings.Contains(trimmed, "Authentication suites: SAE"):
+            cur.authType = AuthWPA3SAE
+        case strings.Contains(trimmed, "Authentication suites: 802.1X"):
+            cur.authType = AuthWPAEnterprise
+        }
+    }
+    if cur != nil {
+        result = append(result, *cur)
+    }
+
+    sort.Slice(result, func(i, j int) bool {
+        return result[i].ssid < result[j].ssid
+    })
+
+    return result
+}
+
+// freqToChannel converts a 2.4/5GHz center frequency (MHz) to its
+// channel number, the same mapping iw itself uses for display.
+func freqToChannel(freq int) int {
+    switch {
+    case freq == 2484:
+        return 14
+    case freq >= 2412 && freq <= 2472:
+        return (freq-2412)/5 + 1
+    case freq >= 5000 && freq < 6000:
+        return (freq-5000) / 5
+    default:
+        return 0
+    }
+}
+
+// wpaCtrlScanner talks to wpa_supplicant's UNIX control socket directly,
+// avoiding a dependency on iw for systems where NetworkManager or a bare
+// wpa_supplicant already owns the interface.
+type wpaCtrlScanner struct{}
+
+// scanPollInterval/scanPollTimeout bound how long Scan waits for
+// wpa_supplicant to report CTRL-EVENT-SCAN-RESULTS before giving up;
+// real scans routinely take longer than a fixed couple of seconds.
+const scanPollInterval = 500 * time.Millisecond
+const scanPollTimeout = 15 * time.Second
+
+func (wpaCtrlScanner) Scan(iface string) ([]Network, error) {
+    sockPath := fmt.Sprintf("/run/wpa_supplicant/%s", iface)
+    conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: sockPath, Net: "unixgram"})
+    if err != nil {
+        return nil, fmt.Errorf("connect to wpa_supplicant control socket: %w", err)
+    }
+    defer conn.Close()
+
+    // ATTACH subscribes this socket to unsolicited event messages (like
+    // CTRL-EVENT-SCAN-RESULTS) in addition to command replies, so we can
+    // tell when the scan actually finished instead of guessing.
+    if reply, err := wpaCtrlCommand(conn, "ATTACH"); err != nil {
+        return nil, fmt.Errorf("ATTACH: %w", err)
+    } else if !strings.HasPrefix(reply, "OK") {
+        return nil, fmt.Errorf("ATTACH failed: %s", strings.TrimSpace(reply))
+    }
+    defer wpaCtrlCommand(conn, "DETACH")
+
+    if _, err := wpaCtrlCommand(conn, "SCAN"); err != nil {
+        return nil, fmt.Errorf("SCAN: %w", err)
+    }
+
+    if err := waitForScanResults(conn); err != nil {
+        return nil, err
+    }
+
+    reply, err := wpaCtrlCommand(conn, "SCAN_RESULTS")
+    if err != nil {
+        return nil, fmt.Errorf("SCAN_RESULTS: %w", err)
+    }
+
+    return parseWpaScanResults(reply), nil
+}
+
+// wpaCtrlCommand sends cmd and returns the single reply datagram
+// wpa_supplicant queues for it. Commands and their replies are
+// delivered in order on this socket, so every command's reply must be
+// read before issuing the next one.
+func wpaCtrlCommand(conn *net.UnixConn, cmd string) (string, error) {
+    if _, err := conn.Write([]byte(cmd)); err != nil {
+        return "", err
+    }
+    buf := make([]byte, 64*1024)
+    for {
+        n, err := conn.Read(buf)
+        if err != nil {
+            return "", err
+        }
+        reply := string(buf[:n])
+        // once ATTACH'd, unsolicited events ("<level>CTRL-EVENT-...")
+        // can arrive ahead of the reply; skip them
+        if strings.HasPrefix(reply, "<") {
+            continue
+        }
+        return reply, nil
+    }
+}
+
+// waitForScanResults polls the (ATTACH'd) control socket for the
+// unsolicited CTRL-EVENT-SCAN-RESULTS event, giving up after
+// scanPollTimeout.
+func waitForScanResults(conn *net.UnixConn) error {
+    deadline := time.Now().Add(scanPollTimeout)
+    buf := make([]byte, 4096)
+    for time.Now().Before(deadline) {
+        conn.SetReadDeadline(time.Now().Add(scanPollInterval))
+        n, err := conn.Read(buf)
+        if err != nil {
+            if ne, ok := err.(net.Error); ok && ne.Timeout() {
+                continue
+            }
+            return err
+        }
+        if strings.Contains(string(buf[:n]), "CTRL-EVENT-SCAN-RESULTS") {
+            conn.SetReadDeadline(time.Time{})
+            return nil
+        }
+    }
+    return fmt.Errorf("timed out waiting for scan results")
+}
+
+// parseWpaScanResults parses SCAN_RESULTS output, a header line
+// followed by one "bssid / freq / signal / flags / ssid" row per AP.
+func parseWpaScanResults(output string) []Network {
+    result := []Network{}
+    lines := strings.Split(output, "\n")
+    if len(lines) < 2 {
+        return result
+    }
+    for _, line := range lines[1:] {
+        if line == "" {
+            continue
+        }
+        fields := strings.SplitN(line, "\t", 5)
+        if len(fields) < 5 {
+            continue
+        }
+        freq, _ := strconv.Atoi(fields[1])
+        rssi, _ := strconv.Atoi(fields[2])
+        flags := fields[3]
+        network := Network{
+            ssid:      fields[4],
+            rssi:      rssi,
+            frequency: freq,
+            channel:   freqToChannel(freq),
+            authType:  authFromFlags(flags),
+            cipher:    cipherFromFlags(flags),
+        }
+        result = append(result, network)
+    }
+
+    sort.Slice(result, func(i, j int) bool {
+        return result[i].ssid < result[j].ssid
+    })
+
+    return result
+}
+
+// authFromFlags classifies a wpa_cli "flags" column, e.g.
+// "[WPA2-EAP-CCMP][ESS]" or "[WPA3-SAE-CCMP][ESS]".
+func authFromFlags(flags string) Auth {
+    switch {
+    case strings.Contains(flags, "SAE"):
+        return AuthWPA3SAE
+    case strings.Contains(flags, "EAP"):
+        return AuthWPAEnterprise
+    case strings.Contains(flags, "WPA"):
+        return AuthWPAPersonal
+    case strings.Contains(flags, "WEP"):
+        return AuthWEP
+    default:
+        return AuthOpen
+    }
+}
+
+// cipherFromFlags pulls the cipher suite out of a wpa_cli "flags" entry
+// like "[WPA2-EAP-CCMP][ESS]", e.g. "CCMP".
+func cipherFromFlags(flags string) string {
+    start := strings.Index(flags, "[")
+    end := strings.Index(flags, "]")
+    if start < 0 || end <= start {
+        return ""
+    }
+    parts := strings.Split(flags[start+1:end], "-")
+    if len(parts) < 2 {
+        // e.g. "[ESS]" or "[WEP]": no cipher suite listed
+        return ""
+    }
+    return parts[len(parts)-1]
+}
@@ -0,0 +1,97 @@
+// +build linux
+
+//
+//  Copyright (c) 2020 Oleksandr Tymoshenko <gonzo@bluezbox.com>
+//
+//  Redistribution and use in source and binary forms, with or without
+//  modification, are permitted provided that the following conditions
+//  are met:
+//  1. Redistributions of source code must retain the above copyright
+//     notice unmodified, this list of conditions, and the following
+//     disclaimer.
+//  2. Redistributions in binary form must reproduce the above copyright
+//     notice, this list of conditions and the following disclaimer in the
+//     documentation and/or other materials provided with the distribution.
+//
+//  THIS SOFTWARE IS PROVIDED BY THE AUTHOR AND CONTRIBUTORS ``AS IS'' AND
+//  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+//  IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+//  ARE DISCLAIMED.  IN NO EVENT SHALL THE AUTHOR OR CONTRIBUTORS BE LIABLE
+//  FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+//  DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS
+//  OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+//  HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT
+//  LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY
+//  OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF
+//  SUCH DAMAGE.
+//
+
+package main
+
+import "testing"
+
+func TestParseIwScan(t *testing.T) {
+    output := `BSS aa:bb:cc:dd:ee:ff(on wlan0)
+	SSID: home-network
+	signal: -42.00 dBm
+	freq: 2437
+	RSN:	 * Version: 1
+		 * Group cipher: CCMP
+		 * Pairwise ciphers: CCMP
+		 * Authentication suites: PSK
+BSS 11:22:33:44:55:66(on wlan0)
+	SSID: office-eap
+	signal: -70.00 dBm
+	freq: 5180
+	RSN:	 * Version: 1
+		 * Group cipher: CCMP
+		 * Pairwise ciphers: CCMP
+		 * Authentication suites: 802.1X
+`
+    networks := parseIwScan(output)
+    if len(networks) != 2 {
+        t.Fatalf("got %d networks, want 2", len(networks))
+    }
+
+    home := networks[0]
+    if home.ssid != "home-network" || home.rssi != -42 || home.channel != 6 {
+        t.Fatalf("home network = %+v, want ssid=home-network rssi=-42 channel=6", home)
+    }
+    if home.authType != AuthWPAPersonal {
+        t.Fatalf("home authType = %v, want AuthWPAPersonal", home.authType)
+    }
+    if home.cipher != "CCMP" {
+        t.Fatalf("home cipher = %q, want CCMP", home.cipher)
+    }
+
+    office := networks[1]
+    if office.authType != AuthWPAEnterprise {
+        t.Fatalf("office authType = %v, want AuthWPAEnterprise", office.authType)
+    }
+}
+
+func TestParseWpaScanResults(t *testing.T) {
+    output := "bssid / frequency / signal level / flags / ssid\n" +
+        "aa:bb:cc:dd:ee:ff\t2437\t-42\t[WPA2-PSK-CCMP][ESS]\thome-network\n" +
+        "11:22:33:44:55:66\t5180\t-70\t[WPA2-EAP-CCMP][ESS]\toffice-eap\n"
+
+    networks := parseWpaScanResults(output)
+    if len(networks) != 2 {
+        t.Fatalf("got %d networks, want 2", len(networks))
+    }
+    if networks[0].ssid != "home-network" || networks[0].authType != AuthWPAPersonal || networks[0].cipher != "CCMP" {
+        t.Fatalf("networks[0] = %+v", networks[0])
+    }
+    if networks[1].ssid != "office-eap" || networks[1].authType != AuthWPAEnterprise || networks[1].cipher != "CCMP" {
+        t.Fatalf("networks[1] = %+v", networks[1])
+    }
+}
+
+func TestFreqToChannel(t *testing.T) {
+    cases := map[int]int{2412: 1, 2437: 6, 2472: 13, 2484: 14, 5180: 36}
+    for freq, want := range cases {
+        if got := freqToChannel(freq); got != want {
+            t.Errorf("freqToChannel(%d) = %d, want %d", freq, got, want)
+        }
+    }
+}
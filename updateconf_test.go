@@ -0,0 +1,76 @@
+//
+//  Copyright (c) 2020 Oleksandr Tymoshenko <gonzo@bluezbox.com>
+//
+//  Redistribution and use in source and binary forms, with or without
+//  modification, are permitted provided that the following conditions
+//  are met:
+//  1. Redistributions of source code must retain the above copyright
+//     notice unmodified, this list of conditions, and the following
+//     disclaimer.
+//  2. Redistributions in binary form must reproduce the above copyright
+//     notice, this list of conditions and the following disclaimer in the
+//     documentation and/or other materials provided with the distribution.
+//
+//  THIS SOFTWARE IS PROVIDED BY THE AUTHOR AND CONTRIBUTORS ``AS IS'' AND
+//  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+//  IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+//  ARE DISCLAIMED.  IN NO EVENT SHALL THE AUTHOR OR CONTRIBUTORS BE LIABLE
+//  FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+//  DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS
+//  OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+//  HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT
+//  LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY
+//  OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF
+//  SUCH DAMAGE.
+//
+
+package main
+
+import (
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+
+func TestUpdateConfFileAtomicWriteAndBackup(t *testing.T) {
+    path := filepath.Join(t.TempDir(), "wpa_supplicant.conf")
+    original := "country=US\n"
+    if err := os.WriteFile(path, []byte(original), 0600); err != nil {
+        t.Fatalf("seed conf file: %v", err)
+    }
+
+    networks := []Network{{ssid: "home", authType: AuthWPAPersonal, psk: "hunter2hunter2"}}
+
+    if changed := updateConfFile(path, networks, true); !changed {
+        t.Fatalf("updateConfFile() = false, want true on first write")
+    }
+
+    newContent, err := os.ReadFile(path)
+    if err != nil {
+        t.Fatalf("read conf: %v", err)
+    }
+    if !strings.Contains(string(newContent), original) {
+        t.Errorf("conf file lost pre-existing content: %q", newContent)
+    }
+    if !strings.Contains(string(newContent), chaifiMarker) || !strings.Contains(string(newContent), "home") {
+        t.Errorf("conf file missing generated section: %q", newContent)
+    }
+
+    backup, err := os.ReadFile(path + ".bak")
+    if err != nil {
+        t.Fatalf("read backup: %v", err)
+    }
+    if string(backup) != original {
+        t.Errorf("backup = %q, want %q (the pre-write content)", backup, original)
+    }
+
+    // no-op: same networks again should report nothing changed
+    if changed := updateConfFile(path, networks, true); changed {
+        t.Errorf("updateConfFile() = true on unchanged input, want false")
+    }
+
+    if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+        t.Errorf("tmp file should be renamed away, got err=%v", err)
+    }
+}